@@ -0,0 +1,78 @@
+/**
+# Copyright (c) 2024, NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpu
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
+)
+
+type probeCommand struct {
+	logger logger.Interface
+}
+
+// NewProbeCommand constructs a gpu probe command.
+func NewProbeCommand(logger logger.Interface) *cli.Command {
+	c := probeCommand{logger: logger}
+
+	return &cli.Command{
+		Name:  "probe",
+		Usage: "Report GPU presence, driver health, and capability support without generating a container spec",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "dev-root",
+				Usage: "the root to search for GPU and DRM devices",
+				Value: "/",
+			},
+			&cli.StringFlag{
+				Name:  "driver-root",
+				Usage: "the root where the NVIDIA driver libraries and configs are installed",
+				Value: "/",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			return c.run(ctx)
+		},
+	}
+}
+
+func (c probeCommand) run(ctx *cli.Context) error {
+	driver := root.New(
+		root.WithLogger(c.logger),
+		root.WithDriverRoot(ctx.String("driver-root")),
+	)
+
+	result, err := discover.Probe(c.logger, driver, ctx.String("dev-root"))
+	if err != nil {
+		return fmt.Errorf("failed to probe GPU devices: %v", err)
+	}
+
+	output, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal probe result: %v", err)
+	}
+
+	fmt.Fprintln(ctx.App.Writer, string(output))
+
+	return nil
+}
@@ -0,0 +1,43 @@
+/**
+# Copyright (c) 2024, NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/gpu"
+)
+
+func main() {
+	logger := logrus.New()
+
+	app := &cli.App{
+		Name:  "nvidia-ctk",
+		Usage: "Tools to configure the NVIDIA Container Toolkit",
+		Commands: []*cli.Command{
+			gpu.NewCommand(logger),
+		},
+	}
+
+	if err := app.Run(os.Args); err != nil {
+		logger.Errorf("%v", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,49 @@
+/**
+# Copyright (c) 2024, NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package image
+
+import "fmt"
+
+// VisibleDevices represents the set of devices requested to be visible inside a
+// container, as configured through NVIDIA_VISIBLE_DEVICES or an equivalent CDI
+// device request. Entries may be GPU indices, UUIDs, PCI bus IDs, "all", or one
+// of the selection tokens recognised by the discover package (e.g. "count=2",
+// "pci=0000:65:00.0", "vendor=10de").
+type VisibleDevices []string
+
+// NewVisibleDevicesWithCount returns a VisibleDevices that selects the first n GPUs
+// by stable ordering, mirroring the Docker DeviceRequest Count semantics where a
+// negative count means "all available devices".
+func NewVisibleDevicesWithCount(n int) VisibleDevices {
+	if n < 0 {
+		return VisibleDevices{"all"}
+	}
+	return VisibleDevices{fmt.Sprintf("count=%d", n)}
+}
+
+// Has returns whether id is present in the set of visible devices.
+func (d VisibleDevices) Has(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, entry := range d {
+		if entry == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,281 @@
+/**
+# Copyright (c) 2024, NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"fmt"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
+)
+
+// Capability represents a single NVIDIA container capability as used in the
+// moby DeviceRequest API (e.g. "gpu", "nvidia", "compute", "graphics").
+type Capability = string
+
+// Recognised leaf capabilities that select a bundle of mounts, hooks and
+// symlinks required for that use case.
+const (
+	CapabilityCompute  Capability = "compute"
+	CapabilityGraphics Capability = "graphics"
+	CapabilityVideo    Capability = "video"
+	CapabilityDisplay  Capability = "display"
+	CapabilityUtility  Capability = "utility"
+)
+
+// defaultComputeDriverCompatibility and defaultVideoDriverCompatibility are currently
+// empty: no optional compute or video library varies by driver major version today.
+// They exist so that new version-gated libraries can be added the same way as for
+// the graphics bundle, without changing these constructors' signatures again.
+var defaultComputeDriverCompatibility = DriverCompatibility{}
+var defaultVideoDriverCompatibility = DriverCompatibility{}
+
+// NewComputeMountsDiscoverer creates a discoverer for the mounts required for CUDA compute workloads.
+func NewComputeMountsDiscoverer(logger logger.Interface, driver *root.Driver, nvidiaCDIHookPath string, opts ...Option) (Discover, error) {
+	o := resolveOptions(opts...)
+	compatibility := defaultComputeDriverCompatibility
+	if o.driverCompatibility != nil {
+		compatibility = *o.driverCompatibility
+	}
+
+	var optionalLibraries []string
+	if major, err := resolveDriverMajorVersion(driver); err != nil {
+		logger.Warningf("Failed to resolve driver major version: %v; using default compute libraries", err)
+	} else if entry, ok := compatibility.Resolve(major); ok {
+		optionalLibraries = entry.Libraries
+	}
+
+	libraries := NewMounts(
+		logger,
+		driver.Libraries(),
+		driver.Root,
+		append(append([]string{}, mandatoryCapabilityLibraries[CapabilityCompute]...), optionalLibraries...),
+	)
+
+	return libraries, nil
+}
+
+// NewVideoMountsDiscoverer creates a discoverer for the mounts required for hardware video codec workloads.
+func NewVideoMountsDiscoverer(logger logger.Interface, driver *root.Driver, nvidiaCDIHookPath string, opts ...Option) (Discover, error) {
+	o := resolveOptions(opts...)
+	compatibility := defaultVideoDriverCompatibility
+	if o.driverCompatibility != nil {
+		compatibility = *o.driverCompatibility
+	}
+
+	var optionalLibraries []string
+	if major, err := resolveDriverMajorVersion(driver); err != nil {
+		logger.Warningf("Failed to resolve driver major version: %v; using default video libraries", err)
+	} else if entry, ok := compatibility.Resolve(major); ok {
+		optionalLibraries = entry.Libraries
+	}
+
+	libraries := NewMounts(
+		logger,
+		driver.Libraries(),
+		driver.Root,
+		append(append([]string{}, mandatoryCapabilityLibraries[CapabilityVideo]...), optionalLibraries...),
+	)
+
+	return libraries, nil
+}
+
+// NewDisplayMountsDiscoverer creates a discoverer for the mounts required for display capture and output.
+func NewDisplayMountsDiscoverer(logger logger.Interface, driver *root.Driver, nvidiaCDIHookPath string) (Discover, error) {
+	libraries := NewMounts(
+		logger,
+		driver.Libraries(),
+		driver.Root,
+		mandatoryCapabilityLibraries[CapabilityDisplay],
+	)
+
+	return libraries, nil
+}
+
+// NewUtilityMountsDiscoverer creates a discoverer for the mounts required to run NVIDIA management utilities such as nvidia-smi.
+func NewUtilityMountsDiscoverer(logger logger.Interface, driver *root.Driver, nvidiaCDIHookPath string) (Discover, error) {
+	binaries := NewMounts(
+		logger,
+		driver.Libraries(),
+		driver.Root,
+		mandatoryCapabilityLibraries[CapabilityUtility],
+	)
+
+	return binaries, nil
+}
+
+// capabilityDiscoverers maps a single capability to the constructor used to resolve its
+// mounts, hooks and symlinks. These are host-wide and not scoped to any particular GPU;
+// device scoping is applied separately by NewCapabilityDiscoverer via its DRM node merge.
+func capabilityDiscoverers(logger logger.Interface, driver *root.Driver, nvidiaCDIHookPath string) map[Capability]func() (Discover, error) {
+	return map[Capability]func() (Discover, error){
+		CapabilityGraphics: func() (Discover, error) {
+			return NewGraphicsMountsDiscoverer(logger, driver, nvidiaCDIHookPath)
+		},
+		CapabilityCompute: func() (Discover, error) {
+			return NewComputeMountsDiscoverer(logger, driver, nvidiaCDIHookPath)
+		},
+		CapabilityVideo: func() (Discover, error) {
+			return NewVideoMountsDiscoverer(logger, driver, nvidiaCDIHookPath)
+		},
+		CapabilityDisplay: func() (Discover, error) {
+			return NewDisplayMountsDiscoverer(logger, driver, nvidiaCDIHookPath)
+		},
+		CapabilityUtility: func() (Discover, error) {
+			return NewUtilityMountsDiscoverer(logger, driver, nvidiaCDIHookPath)
+		},
+	}
+}
+
+// NewCapabilityDiscoverer resolves a set of requested capabilities to a single Discover.
+//
+// caps follows the structured form used by the moby DeviceRequest API: a list
+// of capability groups where each group is an AND-set and the outer list is
+// an OR-set. For example [["gpu", "nvidia", "compute"], ["gpu", "nvidia", "graphics"]]
+// requests compute OR graphics.
+//
+// Capabilities such as "gpu" and "nvidia" are accepted but do not contribute
+// any additional mounts, hooks or devices of their own; they exist purely to
+// match the moby capability vocabulary.
+//
+// devices scopes the DRM device nodes included in the result to the requested
+// GPUs; the driver libraries, configs and binaries mounted by each capability
+// bundle are host-wide and are not affected by devices.
+func NewCapabilityDiscoverer(logger logger.Interface, driver *root.Driver, devices image.VisibleDevices, nvidiaCDIHookPath string, caps [][]string) (Discover, error) {
+	discoverers := capabilityDiscoverers(logger, driver, nvidiaCDIHookPath)
+
+	merged, err := mergeCapabilitySets(discoverers, caps)
+	if err != nil {
+		return nil, err
+	}
+
+	drmNodes, err := NewDRMNodesDiscoverer(logger, devices, driver.Root, nvidiaCDIHookPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DRM device discoverer: %v", err)
+	}
+
+	return newDedupedDiscoverer(Merge(merged, drmNodes)), nil
+}
+
+// mergeCapabilitySets resolves caps against discoverers, ANDing the constructors
+// named within a group and ORing across groups. It contains no behaviour beyond
+// that composition, which keeps it testable independently of how the individual
+// capability bundles are constructed.
+func mergeCapabilitySets(discoverers map[Capability]func() (Discover, error), caps [][]string) (Discover, error) {
+	var orSet []Discover
+	for _, andSet := range caps {
+		var andDiscoverers []Discover
+		for _, c := range andSet {
+			construct, ok := discoverers[c]
+			if !ok {
+				continue
+			}
+			d, err := construct()
+			if err != nil {
+				return nil, fmt.Errorf("failed to construct discoverer for capability %q: %v", c, err)
+			}
+			andDiscoverers = append(andDiscoverers, d)
+		}
+		if len(andDiscoverers) == 0 {
+			continue
+		}
+		orSet = append(orSet, Merge(andDiscoverers...))
+	}
+
+	if len(orSet) == 0 {
+		return None{}, nil
+	}
+
+	return Merge(orSet...), nil
+}
+
+// dedupedDiscoverer wraps a Discover and removes duplicate mounts, devices and hooks.
+//
+// This is required since the same mount or hook may be requested by more
+// than one capability group (e.g. both the "graphics" and "compute" bundles
+// may mount the same driver library).
+type dedupedDiscoverer struct {
+	Discover
+}
+
+var _ Discover = (*dedupedDiscoverer)(nil)
+
+func newDedupedDiscoverer(d Discover) Discover {
+	return &dedupedDiscoverer{Discover: d}
+}
+
+// Devices returns the deduplicated set of devices from the wrapped discoverer.
+func (d dedupedDiscoverer) Devices() ([]Device, error) {
+	devices, err := d.Discover.Devices()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var deduped []Device
+	for _, device := range devices {
+		if seen[device.Path] {
+			continue
+		}
+		seen[device.Path] = true
+		deduped = append(deduped, device)
+	}
+
+	return deduped, nil
+}
+
+// Mounts returns the deduplicated set of mounts from the wrapped discoverer.
+func (d dedupedDiscoverer) Mounts() ([]Mount, error) {
+	mounts, err := d.Discover.Mounts()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var deduped []Mount
+	for _, mount := range mounts {
+		if seen[mount.Path] {
+			continue
+		}
+		seen[mount.Path] = true
+		deduped = append(deduped, mount)
+	}
+
+	return deduped, nil
+}
+
+// Hooks returns the deduplicated set of hooks from the wrapped discoverer.
+func (d dedupedDiscoverer) Hooks() ([]Hook, error) {
+	hooks, err := d.Discover.Hooks()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var deduped []Hook
+	for _, hook := range hooks {
+		key := fmt.Sprintf("%s %v", hook.Path, hook.Args)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		deduped = append(deduped, hook)
+	}
+
+	return deduped, nil
+}
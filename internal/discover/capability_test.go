@@ -0,0 +1,135 @@
+/**
+# Copyright (c) 2024, NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+)
+
+// fakeMountDiscoverer is a Discover that returns a fixed set of mounts, for
+// exercising the AND/OR composition and dedup logic without constructing real
+// driver-backed discoverers.
+type fakeMountDiscoverer struct {
+	None
+	mounts []Mount
+}
+
+func (f fakeMountDiscoverer) Mounts() ([]Mount, error) {
+	return f.mounts, nil
+}
+
+func mountPaths(t *testing.T, d Discover) []string {
+	t.Helper()
+
+	mounts, err := d.Mounts()
+	if err != nil {
+		t.Fatalf("unexpected error getting mounts: %v", err)
+	}
+
+	var paths []string
+	for _, m := range mounts {
+		paths = append(paths, m.Path)
+	}
+	sort.Strings(paths)
+
+	return paths
+}
+
+func TestMergeCapabilitySetsANDOR(t *testing.T) {
+	discoverers := map[Capability]func() (Discover, error){
+		"compute": func() (Discover, error) {
+			return fakeMountDiscoverer{mounts: []Mount{{Path: "/compute/lib"}}}, nil
+		},
+		"graphics": func() (Discover, error) {
+			return fakeMountDiscoverer{mounts: []Mount{{Path: "/graphics/lib"}}}, nil
+		},
+		"gpu": func() (Discover, error) {
+			return fakeMountDiscoverer{}, nil
+		},
+	}
+
+	// [["gpu", "compute"], ["gpu", "graphics"]] means compute OR graphics.
+	caps := [][]string{
+		{"gpu", "compute"},
+		{"gpu", "graphics"},
+	}
+
+	d, err := mergeCapabilitySets(discoverers, caps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := mountPaths(t, d)
+	want := []string{"/compute/lib", "/graphics/lib"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got mounts %v, want %v", got, want)
+	}
+}
+
+func TestMergeCapabilitySetsUnknownCapabilityIgnored(t *testing.T) {
+	discoverers := map[Capability]func() (Discover, error){
+		"compute": func() (Discover, error) {
+			return fakeMountDiscoverer{mounts: []Mount{{Path: "/compute/lib"}}}, nil
+		},
+	}
+
+	caps := [][]string{{"compute", "made-up-capability"}}
+
+	d, err := mergeCapabilitySets(discoverers, caps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := mountPaths(t, d), []string{"/compute/lib"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got mounts %v, want %v", got, want)
+	}
+}
+
+func TestMergeCapabilitySetsNoMatch(t *testing.T) {
+	discoverers := map[Capability]func() (Discover, error){
+		"compute": func() (Discover, error) {
+			return fakeMountDiscoverer{mounts: []Mount{{Path: "/compute/lib"}}}, nil
+		},
+	}
+
+	d, err := mergeCapabilitySets(discoverers, [][]string{{"unknown"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := mountPaths(t, d); len(got) != 0 {
+		t.Errorf("got mounts %v, want none", got)
+	}
+}
+
+func TestDedupedDiscovererMounts(t *testing.T) {
+	d := newDedupedDiscoverer(fakeMountDiscoverer{
+		mounts: []Mount{
+			{Path: "/lib/a"},
+			{Path: "/lib/a"},
+			{Path: "/lib/b"},
+		},
+	})
+
+	got := mountPaths(t, d)
+	want := []string{"/lib/a", "/lib/b"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got deduped mounts %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,144 @@
+/**
+# Copyright (c) 2024, NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/info/drm"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/info/pci"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/info/proc"
+)
+
+// newCountDRMDeviceFilter creates a filter that matches DRM device nodes for devices
+// selected by count (a "count=N" entry, or "all" for every GPU) or by explicit
+// "index=N" entries, mirroring the Docker DeviceRequest Count and DeviceIDs
+// semantics. GPUs are ordered by ascending PCI BDF to give a selection that is
+// stable across runs.
+//
+// Selection is deliberately opt-in via the "count="/"index=" prefixes rather than
+// bare integers: a bare integer already means "select the GPU with this minor
+// number" to newProcDRMDeviceFilter, and minor number order is not guaranteed to
+// match BDF order. Overloading it here would silently select the wrong physical
+// GPU on hosts where the two orderings diverge.
+func newCountDRMDeviceFilter(devices image.VisibleDevices, devRoot string) (selectDeviceByPath, error) {
+	count, indices, ok := parseCountSelection(devices)
+	if !ok {
+		return nil, nil
+	}
+
+	busIDs, err := allGPUBusIDsByBDF(devRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enumerate GPUs for count-based selection: %v", err)
+	}
+
+	var selected []string
+	if count < 0 {
+		selected = append(selected, busIDs...)
+	} else if count > 0 {
+		if count > len(busIDs) {
+			count = len(busIDs)
+		}
+		selected = append(selected, busIDs[:count]...)
+	}
+	for _, idx := range indices {
+		if idx < 0 || idx >= len(busIDs) {
+			continue
+		}
+		selected = append(selected, busIDs[idx])
+	}
+
+	filter := make(selectDeviceByPath)
+	for _, busID := range selected {
+		drmDeviceNodes, err := drm.GetDeviceNodesByBusID(busID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine DRM devices for %v: %v", busID, err)
+		}
+		for _, drmDeviceNode := range drmDeviceNodes {
+			filter[drmDeviceNode] = true
+		}
+	}
+
+	return filter, nil
+}
+
+// parseCountSelection inspects devices for the "count=", "all" or "index=" selection
+// tokens. ok is false if devices contains none of these, in which case count and
+// indices are meaningless and the caller should fall back to identifier-based
+// matching. Bare integers are intentionally not recognised here; they are already
+// claimed by newProcDRMDeviceFilter as a GPU minor number selector.
+func parseCountSelection(devices image.VisibleDevices) (count int, indices []int, ok bool) {
+	for _, entry := range devices {
+		switch {
+		case entry == "all":
+			return -1, nil, true
+		case strings.HasPrefix(entry, "count="):
+			n, err := strconv.Atoi(strings.TrimPrefix(entry, "count="))
+			if err != nil {
+				continue
+			}
+			if n < 0 {
+				return -1, nil, true
+			}
+			count, ok = n, true
+		case strings.HasPrefix(entry, "index="):
+			idx, err := strconv.Atoi(strings.TrimPrefix(entry, "index="))
+			if err != nil {
+				continue
+			}
+			indices = append(indices, idx)
+			ok = true
+		}
+	}
+
+	return count, indices, ok
+}
+
+// allGPUBusIDsByBDF returns the PCI bus IDs of every GPU visible under devRoot, sorted
+// in ascending BDF order. GPU information is read from /proc/driver/nvidia where
+// available, falling back to PCI sysfs otherwise.
+func allGPUBusIDsByBDF(devRoot string) ([]string, error) {
+	var busIDs []string
+
+	if gpuInformationPaths, err := proc.GetInformationFilePaths(devRoot); err == nil {
+		for _, f := range gpuInformationPaths {
+			info, err := proc.ParseGPUInformationFile(f)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %v: %v", f, err)
+			}
+			busIDs = append(busIDs, info[proc.GPUInfoBusLocation])
+		}
+	}
+
+	if len(busIDs) == 0 {
+		pciDevices, err := pci.GetNvidiaDevices(devRoot)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range pciDevices {
+			busIDs = append(busIDs, d.Address)
+		}
+	}
+
+	sort.Strings(busIDs)
+
+	return busIDs, nil
+}
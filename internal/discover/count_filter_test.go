@@ -0,0 +1,73 @@
+/**
+# Copyright (c) 2024, NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
+)
+
+func TestParseCountSelection(t *testing.T) {
+	testCases := []struct {
+		name        string
+		devices     image.VisibleDevices
+		wantCount   int
+		wantIndices []int
+		wantOK      bool
+	}{
+		{"empty", nil, 0, nil, false},
+		{"plain minor number is not a count/index token", image.VisibleDevices{"0"}, 0, nil, false},
+		{"uuid is not a count/index token", image.VisibleDevices{"GPU-1234"}, 0, nil, false},
+		{"all", image.VisibleDevices{"all"}, -1, nil, true},
+		{"count N", image.VisibleDevices{"count=2"}, 2, nil, true},
+		{"count -1 means all", image.VisibleDevices{"count=-1"}, -1, nil, true},
+		{"single index", image.VisibleDevices{"index=1"}, 0, []int{1}, true},
+		{"multiple indices", image.VisibleDevices{"index=0", "index=2"}, 0, []int{0, 2}, true},
+		{"invalid count is ignored", image.VisibleDevices{"count=not-a-number"}, 0, nil, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			count, indices, ok := parseCountSelection(tc.devices)
+			if ok != tc.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tc.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if count != tc.wantCount {
+				t.Errorf("count = %d, want %d", count, tc.wantCount)
+			}
+			if fmt.Sprint(indices) != fmt.Sprint(tc.wantIndices) {
+				t.Errorf("indices = %v, want %v", indices, tc.wantIndices)
+			}
+		})
+	}
+}
+
+func TestParseCountSelectionDoesNotClaimBareIntegers(t *testing.T) {
+	// Regression test: a bare integer is a GPU minor number selector for
+	// newProcDRMDeviceFilter and must never also be treated as a count/index
+	// selector here, or NVIDIA_VISIBLE_DEVICES=1 could expose the DRM nodes of
+	// two different physical GPUs instead of one.
+	_, _, ok := parseCountSelection(image.VisibleDevices{"1"})
+	if ok {
+		t.Errorf("parseCountSelection claimed a bare integer entry; it must only react to count=/index=/all")
+	}
+}
@@ -0,0 +1,265 @@
+/**
+# Copyright (c) 2024, NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/cuda"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
+)
+
+// SymlinkRule describes a symlink to create once a library matching LibraryPattern
+// has been mounted. The link is created alongside the mounted library, pointing at it.
+type SymlinkRule struct {
+	// LibraryPattern is a filepath.Match pattern matched against the mounted library's filename.
+	LibraryPattern string
+	// LinkName is the filename of the symlink to create in the same directory as the library.
+	LinkName string
+}
+
+// DriverCompatibilityEntry describes the optional libraries, symlinks and config
+// files to request for a range of driver major versions.
+type DriverCompatibilityEntry struct {
+	// MinMajor is the lowest driver major version (inclusive) this entry applies to.
+	MinMajor int
+	// MaxMajor is the highest driver major version (inclusive) this entry applies to.
+	// A value of 0 means "no upper bound".
+	MaxMajor int
+
+	// Libraries are additional library glob patterns to mount.
+	Libraries []string
+	// Symlinks are created once the associated library has been mounted.
+	Symlinks []SymlinkRule
+	// Configs are additional config file paths (relative to driver.Configs()) to bind.
+	Configs []string
+}
+
+// matches returns whether major falls within the entry's [MinMajor, MaxMajor] range.
+func (e DriverCompatibilityEntry) matches(major int) bool {
+	if major < e.MinMajor {
+		return false
+	}
+	if e.MaxMajor != 0 && major > e.MaxMajor {
+		return false
+	}
+	return true
+}
+
+// DriverCompatibility resolves the optional libraries, symlinks and config files
+// required for a given driver major version.
+type DriverCompatibility struct {
+	table []DriverCompatibilityEntry
+}
+
+// defaultGraphicsDriverCompatibility is the built-in compatibility table consulted
+// by NewGraphicsMountsDiscoverer.
+var defaultGraphicsDriverCompatibility = DriverCompatibility{
+	table: []DriverCompatibilityEntry{
+		{
+			MinMajor: 0,
+			MaxMajor: 544,
+			Libraries: []string{
+				"libnvidia-vulkan-producer.so.*",
+				"libnvidia-egl-gbm.so.*",
+				"libnvidia-egl-wayland.so.*",
+			},
+			Symlinks: []SymlinkRule{
+				{LibraryPattern: "libnvidia-vulkan-producer.so.*", LinkName: "libnvidia-vulkan-producer.so"},
+			},
+			Configs: []string{
+				"egl/egl_external_platform.d/15_nvidia_gbm.json",
+				"egl/egl_external_platform.d/10_nvidia_wayland.json",
+			},
+		},
+		{
+			MinMajor: 545,
+			MaxMajor: 0,
+			Libraries: []string{
+				"libnvidia-egl-gbm.so.*",
+				"libnvidia-egl-wayland.so.*",
+			},
+			Configs: []string{
+				"egl/egl_external_platform.d/15_nvidia_gbm.json",
+				"egl/egl_external_platform.d/10_nvidia_wayland.json",
+			},
+		},
+	},
+}
+
+// graphicsVersionUnknownFallback is used by NewGraphicsMountsDiscoverer when the
+// driver major version cannot be determined at all. It requests every optional
+// library, symlink and config known to defaultGraphicsDriverCompatibility so that
+// behaviour degrades to the pre-table "mount everything, tolerate missing files"
+// approach rather than silently dropping any of them.
+var graphicsVersionUnknownFallback = DriverCompatibilityEntry{
+	Libraries: []string{
+		"libnvidia-vulkan-producer.so.*",
+		"libnvidia-egl-gbm.so.*",
+		"libnvidia-egl-wayland.so.*",
+	},
+	Symlinks: []SymlinkRule{
+		{LibraryPattern: "libnvidia-vulkan-producer.so.*", LinkName: "libnvidia-vulkan-producer.so"},
+	},
+	Configs: []string{
+		"egl/egl_external_platform.d/15_nvidia_gbm.json",
+		"egl/egl_external_platform.d/10_nvidia_wayland.json",
+	},
+}
+
+// Resolve returns the entry that applies to the specified driver major version.
+//
+// If no entry matches exactly, Resolve falls back to the entry with the closest
+// MaxMajor below the requested version, mirroring the version-fallback behaviour
+// used by the COS GPU image's NVIDIA driver installer.
+func (c DriverCompatibility) Resolve(major int) (DriverCompatibilityEntry, bool) {
+	for _, entry := range c.table {
+		if entry.matches(major) {
+			return entry, true
+		}
+	}
+
+	var closest DriverCompatibilityEntry
+	found := false
+	for _, entry := range c.table {
+		if entry.MaxMajor == 0 || entry.MaxMajor >= major {
+			continue
+		}
+		if !found || entry.MaxMajor > closest.MaxMajor {
+			closest = entry
+			found = true
+		}
+	}
+
+	return closest, found
+}
+
+// Option customizes the behaviour of the mount discoverer constructors in this package.
+type Option func(*discovererOptions)
+
+type discovererOptions struct {
+	driverCompatibility *DriverCompatibility
+}
+
+// WithDriverCompatibility overrides the default driver compatibility table used to
+// resolve optional libraries, symlinks and config files. This is primarily useful
+// for tests and for callers supporting custom or pre-release driver branches.
+func WithDriverCompatibility(c DriverCompatibility) Option {
+	return func(o *discovererOptions) {
+		o.driverCompatibility = &c
+	}
+}
+
+func resolveOptions(opts ...Option) *discovererOptions {
+	o := &discovererOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// mandatoryCapabilityLibraries are the globs each capability bundle requests
+// regardless of driver version. It is the single source of truth for the
+// constructors in capability.go and graphics.go, and is also consulted by
+// Probe so that reported capability support never drifts from what the
+// constructors actually request.
+var mandatoryCapabilityLibraries = map[Capability][]string{
+	CapabilityGraphics: {"libnvidia-allocator.so.*"},
+	CapabilityCompute: {
+		"libcuda.so.*",
+		"libnvidia-ml.so.*",
+		"libcublas.so.*",
+		"libcublasLt.so.*",
+	},
+	CapabilityVideo: {
+		"libnvcuvid.so.*",
+		"libnvidia-encode.so.*",
+	},
+	CapabilityDisplay: {
+		"libnvidia-fbc.so.*",
+		"libnvidia-ifr.so.*",
+	},
+	CapabilityUtility: {
+		"nvidia-smi",
+	},
+}
+
+// capabilityDriverCompatibility maps a capability to the driver compatibility table
+// consulted by its constructor, for capabilities whose optional libraries vary by
+// driver major version.
+var capabilityDriverCompatibility = map[Capability]DriverCompatibility{
+	CapabilityGraphics: defaultGraphicsDriverCompatibility,
+	CapabilityCompute:  defaultComputeDriverCompatibility,
+	CapabilityVideo:    defaultVideoDriverCompatibility,
+}
+
+// expectedCapabilityLibraries returns the full set of library globs a capability's
+// constructor would request for the installed driver, including the version-gated
+// optional libraries resolved from its compatibility table. It mirrors the
+// resolution performed inline by NewGraphicsMountsDiscoverer, NewComputeMountsDiscoverer
+// and NewVideoMountsDiscoverer so that callers such as Probe stay in sync with them
+// by construction rather than via a separately maintained list.
+func expectedCapabilityLibraries(capability Capability, driver *root.Driver) []string {
+	globs := append([]string{}, mandatoryCapabilityLibraries[capability]...)
+
+	compatibility, hasTable := capabilityDriverCompatibility[capability]
+	if !hasTable {
+		return globs
+	}
+
+	major, err := resolveDriverMajorVersion(driver)
+	if err != nil {
+		if capability == CapabilityGraphics {
+			return append(globs, graphicsVersionUnknownFallback.Libraries...)
+		}
+		return globs
+	}
+
+	if entry, ok := compatibility.Resolve(major); ok {
+		globs = append(globs, entry.Libraries...)
+	}
+
+	return globs
+}
+
+// resolveDriverMajorVersion determines the major version of the installed NVIDIA driver
+// by locating libcuda.so, mirroring the approach used by newXorgDiscoverer.
+func resolveDriverMajorVersion(driver *root.Driver) (int, error) {
+	libCudaPaths, err := cuda.New(
+		driver.Libraries(),
+	).Locate(".*.*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to locate libcuda.so: %v", err)
+	}
+	libcudaPath := libCudaPaths[0]
+
+	version := strings.TrimPrefix(filepath.Base(libcudaPath), "libcuda.so.")
+	if version == "" {
+		return 0, fmt.Errorf("failed to determine libcuda.so version from path: %q", libcudaPath)
+	}
+
+	major, _, _ := strings.Cut(version, ".")
+	majorVersion, err := strconv.Atoi(major)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse driver major version from %q: %v", version, err)
+	}
+
+	return majorVersion, nil
+}
@@ -0,0 +1,86 @@
+/**
+# Copyright (c) 2024, NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"testing"
+)
+
+func TestDriverCompatibilityResolve(t *testing.T) {
+	compatibility := DriverCompatibility{
+		table: []DriverCompatibilityEntry{
+			{MinMajor: 0, MaxMajor: 470, Libraries: []string{"old.so"}},
+			{MinMajor: 471, MaxMajor: 544, Libraries: []string{"mid.so"}},
+			{MinMajor: 545, MaxMajor: 0, Libraries: []string{"new.so"}},
+		},
+	}
+
+	testCases := []struct {
+		name   string
+		major  int
+		want   string
+		wantOK bool
+	}{
+		{"within lowest range", 400, "old.so", true},
+		{"exact lower boundary", 471, "mid.so", true},
+		{"exact upper boundary", 544, "mid.so", true},
+		{"within unbounded range", 600, "new.so", true},
+		{"below any known range falls back to none", -1, "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			entry, ok := compatibility.Resolve(tc.major)
+			if ok != tc.wantOK {
+				t.Fatalf("Resolve(%d) ok = %v, want %v", tc.major, ok, tc.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if len(entry.Libraries) != 1 || entry.Libraries[0] != tc.want {
+				t.Errorf("Resolve(%d) = %+v, want library %q", tc.major, entry, tc.want)
+			}
+		})
+	}
+}
+
+func TestDriverCompatibilityResolveClosestLowerRangeFallback(t *testing.T) {
+	compatibility := DriverCompatibility{
+		table: []DriverCompatibilityEntry{
+			{MinMajor: 0, MaxMajor: 390, Libraries: []string{"ancient.so"}},
+			{MinMajor: 391, MaxMajor: 470, Libraries: []string{"old.so"}},
+		},
+	}
+
+	// 999 matches no entry exactly; it should fall back to the entry with the
+	// closest (highest) MaxMajor below it, i.e. the 391-470 entry, not the oldest one.
+	entry, ok := compatibility.Resolve(999)
+	if !ok {
+		t.Fatalf("Resolve(999) ok = false, want true (closest-lower-range fallback)")
+	}
+	if len(entry.Libraries) != 1 || entry.Libraries[0] != "old.so" {
+		t.Errorf("Resolve(999) = %+v, want closest lower entry with library %q", entry, "old.so")
+	}
+}
+
+func TestDriverCompatibilityResolveEmptyTable(t *testing.T) {
+	var compatibility DriverCompatibility
+
+	if _, ok := compatibility.Resolve(545); ok {
+		t.Errorf("Resolve on empty table: ok = true, want false")
+	}
+}
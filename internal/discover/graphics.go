@@ -49,35 +49,47 @@ func NewDRMNodesDiscoverer(logger logger.Interface, devices image.VisibleDevices
 }
 
 // NewGraphicsMountsDiscoverer creates a discoverer for the mounts required by graphics tools such as vulkan.
-func NewGraphicsMountsDiscoverer(logger logger.Interface, driver *root.Driver, nvidiaCDIHookPath string) (Discover, error) {
+func NewGraphicsMountsDiscoverer(logger logger.Interface, driver *root.Driver, nvidiaCDIHookPath string, opts ...Option) (Discover, error) {
+	o := resolveOptions(opts...)
+	compatibility := defaultGraphicsDriverCompatibility
+	if o.driverCompatibility != nil {
+		compatibility = *o.driverCompatibility
+	}
+
+	var optionalLibraries, optionalConfigs []string
+	var symlinkRules []SymlinkRule
+	if major, err := resolveDriverMajorVersion(driver); err != nil {
+		logger.Warningf("Failed to resolve driver major version: %v; mounting all optional graphics libraries", err)
+		optionalLibraries = graphicsVersionUnknownFallback.Libraries
+		optionalConfigs = graphicsVersionUnknownFallback.Configs
+		symlinkRules = graphicsVersionUnknownFallback.Symlinks
+	} else if entry, ok := compatibility.Resolve(major); ok {
+		optionalLibraries = entry.Libraries
+		optionalConfigs = entry.Configs
+		symlinkRules = entry.Symlinks
+	}
+
 	libraries := NewMounts(
 		logger,
 		driver.Libraries(),
 		driver.Root,
-		[]string{
-			"libnvidia-egl-gbm.so.*",
-			"libnvidia-egl-wayland.so.*",
-			"libnvidia-allocator.so.*",
-			"libnvidia-vulkan-producer.so.*",
-		},
+		append(append([]string{}, mandatoryCapabilityLibraries[CapabilityGraphics]...), optionalLibraries...),
 	)
 
 	jsonMounts := NewMounts(
 		logger,
 		driver.Configs(),
 		driver.Root,
-		[]string{
+		append([]string{
 			"glvnd/egl_vendor.d/10_nvidia.json",
 			"vulkan/icd.d/nvidia_icd.json",
 			"vulkan/icd.d/nvidia_layers.json",
 			"vulkan/implicit_layer.d/nvidia_layers.json",
-			"egl/egl_external_platform.d/15_nvidia_gbm.json",
-			"egl/egl_external_platform.d/10_nvidia_wayland.json",
 			"nvidia/nvoptix.bin",
-		},
+		}, optionalConfigs...),
 	)
 
-	symlinks := newGraphicsDriverSymlinks(logger, libraries, nvidiaCDIHookPath)
+	symlinks := newGraphicsDriverSymlinks(logger, libraries, nvidiaCDIHookPath, symlinkRules)
 	xorg := optionalXorgDiscoverer(logger, driver, nvidiaCDIHookPath)
 
 	discover := Merge(
@@ -95,15 +107,17 @@ type graphicsDriverSymlinks struct {
 	logger            logger.Interface
 	libraries         Discover
 	nvidiaCDIHookPath string
+	rules             []SymlinkRule
 }
 
 var _ Discover = (*graphicsDriverSymlinks)(nil)
 
-func newGraphicsDriverSymlinks(logger logger.Interface, libraries Discover, nvidiaCDIHookPath string) Discover {
+func newGraphicsDriverSymlinks(logger logger.Interface, libraries Discover, nvidiaCDIHookPath string, rules []SymlinkRule) Discover {
 	return &graphicsDriverSymlinks{
 		logger:            logger,
 		libraries:         libraries,
 		nvidiaCDIHookPath: nvidiaCDIHookPath,
+		rules:             rules,
 	}
 }
 
@@ -129,10 +143,18 @@ func (d graphicsDriverSymlinks) Hooks() ([]Hook, error) {
 			links = append(links, fmt.Sprintf("%s::%s", target, linkPath))
 		}
 
-		// Address the vulkan-producer lib for nvidia drivers prior driver version 545
-		if strings.HasPrefix(filename, "libnvidia-vulkan-producer.so.") {
+		// Apply any version-specific symlinks from the driver compatibility table,
+		// e.g. the vulkan-producer lib for nvidia drivers prior to driver version 545.
+		for _, rule := range d.rules {
+			matched, err := filepath.Match(rule.LibraryPattern, filename)
+			if err != nil {
+				return nil, fmt.Errorf("invalid symlink pattern %q: %v", rule.LibraryPattern, err)
+			}
+			if !matched {
+				continue
+			}
 			linkDir := filepath.Dir(mount.Path)
-			linkPath := filepath.Join(linkDir, "libnvidia-vulkan-producer.so")
+			linkPath := filepath.Join(linkDir, rule.LinkName)
 			links = append(links, fmt.Sprintf("%s::%s", filename, linkPath))
 		}
 	}
@@ -242,7 +264,7 @@ func newDRMDeviceDiscoverer(logger logger.Interface, devices image.VisibleDevice
 		},
 	)
 
-	filter, err := newDRMDeviceFilter(devices, devRoot)
+	filter, err := newDRMDeviceFilter(logger, devices, devRoot)
 	if err != nil {
 		return nil, fmt.Errorf("failed to construct DRM device filter: %v", err)
 	}
@@ -258,7 +280,46 @@ func newDRMDeviceDiscoverer(logger logger.Interface, devices image.VisibleDevice
 }
 
 // newDRMDeviceFilter creates a filter that matches DRM devices nodes for the visible devices.
-func newDRMDeviceFilter(devices image.VisibleDevices, devRoot string) (Filter, error) {
+//
+// GPU minor numbers, UUIDs and bus IDs are matched only through /proc/driver/nvidia,
+// which requires the NVIDIA kernel module to be loaded. Entries that use one of the
+// "pci=", "vendor=" or "pci-bdf=" selection tokens are matched from PCI sysfs (see
+// newPCIDRMDeviceFilter), and "count=", "index=" and "all" entries against the
+// BDF-ordered GPU list (see newCountDRMDeviceFilter); both work without the kernel
+// module loaded. The resulting filter is the union of all three.
+func newDRMDeviceFilter(logger logger.Interface, devices image.VisibleDevices, devRoot string) (Filter, error) {
+	filter := make(selectDeviceByPath)
+
+	procFilter, err := newProcDRMDeviceFilter(devices, devRoot)
+	if err != nil {
+		logger.Warningf("Failed to construct DRM device filter from /proc/driver/nvidia: %v; only PCI sysfs and count-based selection tokens will apply", err)
+	}
+	for path := range procFilter {
+		filter[path] = true
+	}
+
+	pciFilter, err := newPCIDRMDeviceFilter(devices, devRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct DRM device filter from PCI sysfs: %v", err)
+	}
+	for path := range pciFilter {
+		filter[path] = true
+	}
+
+	countFilter, err := newCountDRMDeviceFilter(devices, devRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct count-based DRM device filter: %v", err)
+	}
+	for path := range countFilter {
+		filter[path] = true
+	}
+
+	return filter, nil
+}
+
+// newProcDRMDeviceFilter creates a filter that matches DRM device nodes for the visible
+// devices using GPU information exposed under /proc/driver/nvidia.
+func newProcDRMDeviceFilter(devices image.VisibleDevices, devRoot string) (selectDeviceByPath, error) {
 	gpuInformationPaths, err := proc.GetInformationFilePaths(devRoot)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read GPU information: %v", err)
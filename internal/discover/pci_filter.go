@@ -0,0 +1,134 @@
+/**
+# Copyright (c) 2024, NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/info/pci"
+)
+
+// newPCIDRMDeviceFilter creates a filter that matches DRM device nodes for the visible
+// devices by reading PCI vendor, device and DRM information directly from sysfs. This
+// works even if the NVIDIA kernel module is not loaded.
+//
+// Only entries in devices that use one of the following selection tokens are
+// matched; GPU minor numbers, UUIDs and bus IDs are ignored here and are matched
+// by newProcDRMDeviceFilter instead:
+//
+//	pci=<bdf>            select the device at the specified PCI bus:device.function address
+//	pci-bdf=<bdf>         equivalent to pci=<bdf>
+//	vendor=<id>           select all devices with the specified PCI vendor ID
+//	vendor=<id>,device=<id> select devices matching both the vendor and device ID
+func newPCIDRMDeviceFilter(devices image.VisibleDevices, devRoot string) (selectDeviceByPath, error) {
+	pciDevices, err := pci.GetNvidiaDevices(devRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enumerate PCI devices: %v", err)
+	}
+
+	filter := make(selectDeviceByPath)
+	for _, entry := range devices {
+		if !isPCISelectionToken(entry) {
+			continue
+		}
+		for _, d := range pciDevices {
+			if !pciDeviceMatchesToken(entry, d) {
+				continue
+			}
+			for _, node := range d.DRMNodes {
+				path := filepath.Join(devRoot, "dev/dri", node.Name)
+				if !drmNodeMatchesDevFile(path, node.Major, node.Minor) {
+					continue
+				}
+				filter[path] = true
+			}
+		}
+	}
+
+	return filter, nil
+}
+
+// isPCISelectionToken returns whether entry uses one of the PCI-based selection tokens.
+func isPCISelectionToken(entry string) bool {
+	return strings.HasPrefix(entry, "pci=") ||
+		strings.HasPrefix(entry, "pci-bdf=") ||
+		strings.HasPrefix(entry, "vendor=")
+}
+
+// pciDeviceMatchesToken returns whether the specified PCI device matches the selection token.
+func pciDeviceMatchesToken(token string, d pci.Device) bool {
+	switch {
+	case strings.HasPrefix(token, "pci-bdf="):
+		return d.Address == strings.TrimPrefix(token, "pci-bdf=")
+	case strings.HasPrefix(token, "pci="):
+		return d.Address == strings.TrimPrefix(token, "pci=")
+	case strings.HasPrefix(token, "vendor="):
+		return pciDeviceMatchesVendorSpec(token, d)
+	}
+	return false
+}
+
+// pciDeviceMatchesVendorSpec matches tokens of the form "vendor=<id>" or "vendor=<id>,device=<id>".
+func pciDeviceMatchesVendorSpec(token string, d pci.Device) bool {
+	for _, field := range strings.Split(token, ",") {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return false
+		}
+
+		key, value := kv[0], normalizeHexID(kv[1])
+		switch key {
+		case "vendor":
+			if normalizeHexID(d.Vendor) != value {
+				return false
+			}
+		case "device":
+			if normalizeHexID(d.DeviceID) != value {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// normalizeHexID strips an optional "0x" prefix and lowercases a hex PCI ID for comparison.
+func normalizeHexID(id string) string {
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(id)), "0x")
+}
+
+// drmNodeMatchesDevFile returns whether the character device at path has the
+// specified major:minor. This guards against the sysfs-derived major/minor
+// being stale: if the node under /dev/dri was removed and recreated with
+// different device numbers since GetNvidiaDevices read sysfs, the filter must
+// not select it.
+func drmNodeMatchesDevFile(path string, major, minor uint64) bool {
+	var stat unix.Stat_t
+	if err := unix.Stat(path, &stat); err != nil {
+		return false
+	}
+
+	rdev := uint64(stat.Rdev)
+	return unix.Major(rdev) == uint32(major) && unix.Minor(rdev) == uint32(minor)
+}
@@ -0,0 +1,101 @@
+/**
+# Copyright (c) 2024, NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"testing"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/info/pci"
+)
+
+func TestIsPCISelectionToken(t *testing.T) {
+	testCases := []struct {
+		entry string
+		want  bool
+	}{
+		{"pci=0000:65:00.0", true},
+		{"pci-bdf=0000:65:00.0", true},
+		{"vendor=10de", true},
+		{"vendor=10de,device=2204", true},
+		{"0", false},
+		{"GPU-12345678-1234-1234-1234-123456789012", false},
+		{"all", false},
+	}
+
+	for _, tc := range testCases {
+		if got := isPCISelectionToken(tc.entry); got != tc.want {
+			t.Errorf("isPCISelectionToken(%q) = %v, want %v", tc.entry, got, tc.want)
+		}
+	}
+}
+
+func TestPCIDeviceMatchesToken(t *testing.T) {
+	device := pci.Device{
+		Address:  "0000:65:00.0",
+		Vendor:   "0x10de",
+		DeviceID: "0x2204",
+	}
+
+	testCases := []struct {
+		name  string
+		token string
+		want  bool
+	}{
+		{"matching bdf", "pci=0000:65:00.0", true},
+		{"matching pci-bdf alias", "pci-bdf=0000:65:00.0", true},
+		{"non-matching bdf", "pci=0000:17:00.0", false},
+		{"matching vendor only", "vendor=10de", true},
+		{"matching vendor with 0x prefix", "vendor=0x10de", true},
+		{"non-matching vendor", "vendor=10ad", false},
+		{"matching vendor and device", "vendor=10de,device=2204", true},
+		{"matching vendor wrong device", "vendor=10de,device=1eb8", false},
+		{"unsupported key", "bus=0000:65:00.0", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := pciDeviceMatchesToken(tc.token, device); got != tc.want {
+				t.Errorf("pciDeviceMatchesToken(%q, %+v) = %v, want %v", tc.token, device, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDRMNodeMatchesDevFile(t *testing.T) {
+	// /dev/null is major:minor 1:3 on every Linux system, so it is a stable
+	// stand-in for a DRM device node without requiring mknod privileges.
+	testCases := []struct {
+		name  string
+		path  string
+		major uint64
+		minor uint64
+		want  bool
+	}{
+		{"matching major:minor", "/dev/null", 1, 3, true},
+		{"mismatched minor", "/dev/null", 1, 4, false},
+		{"mismatched major", "/dev/null", 2, 3, false},
+		{"missing path", "/dev/does-not-exist", 1, 3, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := drmNodeMatchesDevFile(tc.path, tc.major, tc.minor); got != tc.want {
+				t.Errorf("drmNodeMatchesDevFile(%q, %d, %d) = %v, want %v", tc.path, tc.major, tc.minor, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+/**
+# Copyright (c) 2024, NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/info/pci"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/info/proc"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/cuda"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
+)
+
+// ProbeGPU describes a single GPU discovered by Probe.
+type ProbeGPU struct {
+	BusID    string   `json:"busID"`
+	DRMNodes []string `json:"drmNodes,omitempty"`
+}
+
+// ProbeCapability reports whether a capability bundle could be satisfied given the
+// files actually present on disk, and which of its underlying globs resolved.
+type ProbeCapability struct {
+	Satisfiable   bool     `json:"satisfiable"`
+	ResolvedGlobs []string `json:"resolvedGlobs,omitempty"`
+	MissingGlobs  []string `json:"missingGlobs,omitempty"`
+}
+
+// ProbeResult is the result of probing a host for NVIDIA GPU presence and capability support.
+type ProbeResult struct {
+	GPUsPresent        bool                           `json:"gpusPresent"`
+	KernelModuleLoaded bool                           `json:"kernelModuleLoaded"`
+	DriverVersion      string                         `json:"driverVersion,omitempty"`
+	GPUs               []ProbeGPU                     `json:"gpus,omitempty"`
+	Capabilities       map[Capability]ProbeCapability `json:"capabilities"`
+}
+
+// Probe reports whether NVIDIA GPUs are present and healthy on the host, without
+// requiring a container spec. It reuses the same discoverers used to construct CDI
+// specs and OCI spec modifications, so the result reflects exactly what a container
+// would actually receive.
+func Probe(logger logger.Interface, driver *root.Driver, devRoot string) (*ProbeResult, error) {
+	result := &ProbeResult{
+		Capabilities: make(map[Capability]ProbeCapability),
+	}
+
+	gpus, kernelModuleLoaded, err := probeGPUs(devRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to probe GPUs: %v", err)
+	}
+	result.GPUs = gpus
+	result.GPUsPresent = len(gpus) > 0
+	result.KernelModuleLoaded = kernelModuleLoaded
+
+	if libCudaPaths, err := cuda.New(driver.Libraries()).Locate(".*.*"); err == nil && len(libCudaPaths) > 0 {
+		result.DriverVersion = strings.TrimPrefix(filepath.Base(libCudaPaths[0]), "libcuda.so.")
+	}
+
+	discoverers := capabilityDiscoverers(logger, driver, "")
+	for capability, construct := range discoverers {
+		result.Capabilities[capability] = probeCapability(capability, driver, construct)
+	}
+
+	return result, nil
+}
+
+// probeGPUs enumerates the GPUs visible under devRoot along with their DRM nodes,
+// preferring /proc/driver/nvidia and falling back to PCI sysfs if the kernel module
+// is not loaded.
+func probeGPUs(devRoot string) ([]ProbeGPU, bool, error) {
+	pciDevices, err := pci.GetNvidiaDevices(devRoot)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to enumerate PCI devices: %v", err)
+	}
+
+	drmNodesByBusID := make(map[string][]string)
+	for _, d := range pciDevices {
+		for _, node := range d.DRMNodes {
+			drmNodesByBusID[d.Address] = append(drmNodesByBusID[d.Address], node.Name)
+		}
+	}
+
+	gpuInformationPaths, procErr := proc.GetInformationFilePaths(devRoot)
+	kernelModuleLoaded := procErr == nil && len(gpuInformationPaths) > 0
+
+	var gpus []ProbeGPU
+	if kernelModuleLoaded {
+		for _, f := range gpuInformationPaths {
+			info, err := proc.ParseGPUInformationFile(f)
+			if err != nil {
+				return nil, kernelModuleLoaded, fmt.Errorf("failed to parse %v: %v", f, err)
+			}
+			busID := info[proc.GPUInfoBusLocation]
+			gpus = append(gpus, ProbeGPU{BusID: busID, DRMNodes: drmNodesByBusID[busID]})
+		}
+		return gpus, kernelModuleLoaded, nil
+	}
+
+	for _, d := range pciDevices {
+		gpus = append(gpus, ProbeGPU{BusID: d.Address, DRMNodes: drmNodesByBusID[d.Address]})
+	}
+
+	return gpus, kernelModuleLoaded, nil
+}
+
+// probeCapability dry-runs a capability's discoverer and records which of its globs
+// resolved to a mount on disk. The expected globs are derived from the same
+// mandatory/version-gated library lists the capability's constructor itself
+// consults, so the probe can never drift out of sync with what a container
+// would actually receive.
+func probeCapability(capability Capability, driver *root.Driver, construct func() (Discover, error)) ProbeCapability {
+	globs := expectedCapabilityLibraries(capability, driver)
+
+	d, err := construct()
+	if err != nil {
+		return ProbeCapability{Satisfiable: false, MissingGlobs: globs}
+	}
+
+	mounts, err := d.Mounts()
+	if err != nil {
+		return ProbeCapability{Satisfiable: false, MissingGlobs: globs}
+	}
+
+	var resolvedNames []string
+	for _, m := range mounts {
+		resolvedNames = append(resolvedNames, filepath.Base(m.HostPath))
+	}
+
+	var resolved, missing []string
+	for _, glob := range globs {
+		if matchesAny(glob, resolvedNames) {
+			resolved = append(resolved, glob)
+		} else {
+			missing = append(missing, glob)
+		}
+	}
+
+	return ProbeCapability{
+		Satisfiable:   len(missing) == 0,
+		ResolvedGlobs: resolved,
+		MissingGlobs:  missing,
+	}
+}
+
+func matchesAny(glob string, names []string) bool {
+	for _, name := range names {
+		if ok, _ := filepath.Match(glob, name); ok {
+			return true
+		}
+	}
+	return false
+}
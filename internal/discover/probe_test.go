@@ -0,0 +1,115 @@
+/**
+# Copyright (c) 2024, NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+)
+
+func TestProbeCapability(t *testing.T) {
+	testCases := []struct {
+		name          string
+		capability    Capability
+		construct     func() (Discover, error)
+		wantSatisfied bool
+		wantMissing   []string
+	}{
+		{
+			name:       "all mandatory globs resolved",
+			capability: CapabilityUtility,
+			construct: func() (Discover, error) {
+				return fakeMountDiscoverer{mounts: []Mount{{HostPath: "/usr/bin/nvidia-smi"}}}, nil
+			},
+			wantSatisfied: true,
+		},
+		{
+			name:       "mandatory glob missing",
+			capability: CapabilityUtility,
+			construct: func() (Discover, error) {
+				return fakeMountDiscoverer{}, nil
+			},
+			wantSatisfied: false,
+			wantMissing:   []string{"nvidia-smi"},
+		},
+		{
+			name:       "construct error leaves every glob missing",
+			capability: CapabilityDisplay,
+			construct: func() (Discover, error) {
+				return nil, fmt.Errorf("failed to construct")
+			},
+			wantSatisfied: false,
+			wantMissing:   []string{"libnvidia-fbc.so.*", "libnvidia-ifr.so.*"},
+		},
+		{
+			name:       "mounts error leaves every glob missing",
+			capability: CapabilityDisplay,
+			construct: func() (Discover, error) {
+				return erroringMountDiscoverer{}, nil
+			},
+			wantSatisfied: false,
+			wantMissing:   []string{"libnvidia-fbc.so.*", "libnvidia-ifr.so.*"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// CapabilityUtility and CapabilityDisplay have no driver compatibility
+			// table, so expectedCapabilityLibraries never dereferences driver and a
+			// nil *root.Driver is safe here.
+			got := probeCapability(tc.capability, nil, tc.construct)
+			if got.Satisfiable != tc.wantSatisfied {
+				t.Errorf("Satisfiable = %v, want %v", got.Satisfiable, tc.wantSatisfied)
+			}
+
+			sort.Strings(got.MissingGlobs)
+			sort.Strings(tc.wantMissing)
+			if fmt.Sprint(got.MissingGlobs) != fmt.Sprint(tc.wantMissing) {
+				t.Errorf("MissingGlobs = %v, want %v", got.MissingGlobs, tc.wantMissing)
+			}
+		})
+	}
+}
+
+// erroringMountDiscoverer is a Discover whose Mounts always fails, for
+// exercising probeCapability's error handling.
+type erroringMountDiscoverer struct {
+	None
+}
+
+func (erroringMountDiscoverer) Mounts() ([]Mount, error) {
+	return nil, fmt.Errorf("failed to resolve mounts")
+}
+
+func TestExpectedCapabilityLibrariesNoDriverCompatibilityTable(t *testing.T) {
+	// CapabilityUtility and CapabilityDisplay have no entry in
+	// capabilityDriverCompatibility, so their expected globs must be exactly
+	// their mandatory globs and must never touch driver. This guards against
+	// the probe silently growing a separately maintained glob list again.
+	testCases := []Capability{CapabilityUtility, CapabilityDisplay}
+
+	for _, capability := range testCases {
+		t.Run(capability, func(t *testing.T) {
+			got := expectedCapabilityLibraries(capability, nil)
+			want := mandatoryCapabilityLibraries[capability]
+			if fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Errorf("expectedCapabilityLibraries(%q, nil) = %v, want %v", capability, got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+/**
+# Copyright (c) 2024, NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+// Package pci provides utilities for discovering GPU devices directly from
+// PCI sysfs. Unlike the proc package, this does not require the NVIDIA
+// kernel module to be loaded or to have populated /proc/driver/nvidia.
+package pci
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// NvidiaVendorID is the PCI vendor ID assigned to NVIDIA Corporation.
+const NvidiaVendorID = "0x10de"
+
+// DRMNode describes a single DRM device node exposed by a PCI device.
+type DRMNode struct {
+	// Name is the node name as it appears under /dev/dri, e.g. "card0" or "renderD128".
+	Name string
+	// Major and Minor are the character device numbers read from the sysfs "dev"
+	// attribute. They are used to confirm that the node under /dev/dri still
+	// refers to the same device sysfs described, not a node that was removed
+	// and recreated with different numbers in the meantime.
+	Major uint64
+	Minor uint64
+}
+
+// Device represents a single PCI device discovered under sysfs.
+type Device struct {
+	// Address is the PCI bus:device.function (BDF) address, e.g. "0000:65:00.0".
+	Address string
+	// Vendor is the hex PCI vendor ID, e.g. "0x10de".
+	Vendor string
+	// DeviceID is the hex PCI device ID, e.g. "0x2204".
+	DeviceID string
+	// DRMNodes are the DRM device nodes exposed by this device.
+	DRMNodes []DRMNode
+}
+
+// GetNvidiaDevices returns the NVIDIA GPUs found under /sys/bus/pci/devices relative to devRoot.
+func GetNvidiaDevices(devRoot string) ([]Device, error) {
+	devices, err := GetDevices(devRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var nvidia []Device
+	for _, d := range devices {
+		if strings.EqualFold(d.Vendor, NvidiaVendorID) {
+			nvidia = append(nvidia, d)
+		}
+	}
+
+	return nvidia, nil
+}
+
+// GetDevices enumerates all PCI devices found under /sys/bus/pci/devices relative to devRoot.
+func GetDevices(devRoot string) ([]Device, error) {
+	sysfsRoot := filepath.Join(devRoot, "sys/bus/pci/devices")
+
+	entries, err := os.ReadDir(sysfsRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %v: %v", sysfsRoot, err)
+	}
+
+	var devices []Device
+	for _, entry := range entries {
+		device, err := getDevice(sysfsRoot, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read PCI device %v: %v", entry.Name(), err)
+		}
+		devices = append(devices, device)
+	}
+
+	return devices, nil
+}
+
+// getDevice reads the vendor, device and drm/ attributes for a single PCI device.
+func getDevice(sysfsRoot string, address string) (Device, error) {
+	device := Device{Address: address}
+	deviceRoot := filepath.Join(sysfsRoot, address)
+
+	vendor, err := readSysfsAttribute(filepath.Join(deviceRoot, "vendor"))
+	if err != nil {
+		return device, err
+	}
+	device.Vendor = vendor
+
+	deviceID, err := readSysfsAttribute(filepath.Join(deviceRoot, "device"))
+	if err != nil {
+		return device, err
+	}
+	device.DeviceID = deviceID
+
+	drmEntries, err := os.ReadDir(filepath.Join(deviceRoot, "drm"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return device, nil
+		}
+		return device, fmt.Errorf("failed to read drm devices for %v: %v", address, err)
+	}
+
+	for _, drmEntry := range drmEntries {
+		name := drmEntry.Name()
+		if !strings.HasPrefix(name, "card") && !strings.HasPrefix(name, "renderD") {
+			continue
+		}
+
+		major, minor, err := readDevFile(filepath.Join(deviceRoot, "drm", name, "dev"))
+		if err != nil {
+			return device, fmt.Errorf("failed to read major:minor for %v: %v", name, err)
+		}
+
+		device.DRMNodes = append(device.DRMNodes, DRMNode{
+			Name:  name,
+			Major: major,
+			Minor: minor,
+		})
+	}
+
+	return device, nil
+}
+
+// readDevFile parses a sysfs "dev" file of the form "major:minor".
+func readDevFile(path string) (uint64, uint64, error) {
+	contents, err := readSysfsAttribute(path)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	parts := strings.SplitN(contents, ":", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("unexpected format for %v: %q", path, contents)
+	}
+
+	major, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid major in %v: %v", path, err)
+	}
+	minor, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid minor in %v: %v", path, err)
+	}
+
+	return major, minor, nil
+}
+
+func readSysfsAttribute(path string) (string, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %v: %v", path, err)
+	}
+
+	return strings.ToLower(strings.TrimSpace(string(contents))), nil
+}